@@ -0,0 +1,85 @@
+// Package catalog loads the GPU hashrate table used to turn a prover's
+// raw network speed into an estimated rig composition, so new GPU
+// models can be added via config instead of a code change.
+package catalog
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GPU is one model's hashrate and, optionally, its approximate market
+// price so the report can estimate hardware value.
+type GPU struct {
+	Name        string   `yaml:"name"`
+	HashrateSPS float64  `yaml:"hashrate_sps"`
+	PriceUSD    *float64 `yaml:"price_usd,omitempty"`
+}
+
+// Catalog is the `--catalog` document: the list of GPU models to
+// generate a count column for.
+type Catalog struct {
+	GPUs []GPU `yaml:"gpus"`
+}
+
+// Default is the catalog used when --catalog isn't set, preserving the
+// tool's original hardcoded 3080/4090 columns.
+func Default() *Catalog {
+	return &Catalog{
+		GPUs: []GPU{
+			{Name: "3080", HashrateSPS: 15000},
+			{Name: "4090", HashrateSPS: 43000},
+		},
+	}
+}
+
+// Load reads a GPU catalog from a YAML file.
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cat Catalog
+	if err := yaml.Unmarshal(data, &cat); err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// HasPricing reports whether any GPU in the catalog has a price, which
+// gates whether the "est. hardware value" column is added.
+func (c *Catalog) HasPricing() bool {
+	for _, gpu := range c.GPUs {
+		if gpu.PriceUSD != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Counts estimates how many of each GPU model a prover's network speed
+// (samples/sec) is equivalent to.
+func (c *Catalog) Counts(networkSpeed float64) map[string]int {
+	counts := make(map[string]int, len(c.GPUs))
+	for _, gpu := range c.GPUs {
+		if gpu.HashrateSPS <= 0 {
+			continue
+		}
+		counts[gpu.Name] = int(networkSpeed / gpu.HashrateSPS)
+	}
+	return counts
+}
+
+// EstimatedValue sums count * price over every priced GPU model in
+// counts.
+func (c *Catalog) EstimatedValue(counts map[string]int) float64 {
+	var total float64
+	for _, gpu := range c.GPUs {
+		if gpu.PriceUSD == nil {
+			continue
+		}
+		total += float64(counts[gpu.Name]) * *gpu.PriceUSD
+	}
+	return total
+}
@@ -0,0 +1,91 @@
+// Package charset decodes cluster CSV files that aren't UTF-8 — GBK,
+// GB18030 and Big5 are common for Chinese-language ops spreadsheets and
+// otherwise come out as mojibake in the 标记 column.
+package charset
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/saintfish/chardet"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Reader wraps r so it yields UTF-8, decoding it from name ("utf-8",
+// "gbk", "gb18030", "big5") or, when name is empty, sniffing the first
+// 4KB with github.com/saintfish/chardet, a statistical detector that
+// (unlike golang.org/x/net/html/charset) can actually tell GB18030 and
+// Big5 apart from single-byte charsets instead of defaulting every
+// non-UTF-8 file to windows-1252. A leading UTF-8 BOM is stripped
+// either way so it doesn't end up as a stray rune in the first cell.
+func Reader(r io.Reader, name string) (io.Reader, error) {
+	enc, r, err := resolve(r, name)
+	if err != nil {
+		return nil, err
+	}
+	return stripBOM(transform.NewReader(r, enc.NewDecoder())), nil
+}
+
+// sniffedEncoding maps the IANA charset names chardet reports to the
+// golang.org/x/text encoding this package already knows how to decode.
+// Anything else (Latin-language single-byte charsets, etc.) falls back
+// to UTF-8, matching the cluster files this tool actually sees.
+func sniffedEncoding(charsetName string) encoding.Encoding {
+	switch charsetName {
+	case "GB-18030":
+		return simplifiedchinese.GB18030
+	case "Big5":
+		return traditionalchinese.Big5
+	case "UTF-8":
+		return unicode.UTF8
+	default:
+		return unicode.UTF8
+	}
+}
+
+func resolve(r io.Reader, name string) (encoding.Encoding, io.Reader, error) {
+	switch strings.ToLower(name) {
+	case "":
+		const sniffLen = 4096
+		buf := make([]byte, sniffLen)
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, nil, err
+		}
+		buf = buf[:n]
+
+		rest := io.MultiReader(bytes.NewReader(buf), r)
+		result, err := chardet.NewTextDetector().DetectBest(buf)
+		if err != nil {
+			return unicode.UTF8, rest, nil
+		}
+		return sniffedEncoding(result.Charset), rest, nil
+	case "utf-8", "utf8":
+		return unicode.UTF8, r, nil
+	case "gbk":
+		return simplifiedchinese.GBK, r, nil
+	case "gb18030":
+		return simplifiedchinese.GB18030, r, nil
+	case "big5":
+		return traditionalchinese.Big5, r, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported encoding %q (want utf-8, gbk, gb18030 or big5)", name)
+	}
+}
+
+// stripBOM drops a leading UTF-8 byte-order mark, if present.
+func stripBOM(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	bom, err := br.Peek(3)
+	if err == nil && bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		br.Discard(3)
+	}
+	return br
+}
@@ -0,0 +1,93 @@
+package charset
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// simplifiedLabel and traditionalLabel are the 标记 column content baked
+// into the testdata fixtures below, enough running Chinese text that a
+// statistical detector (unlike golang.org/x/net/html/charset, which only
+// recognizes BOMs/HTML meta tags/valid UTF-8) has the signal to tell
+// GB18030 and Big5 apart from single-byte charsets.
+const (
+	simplifiedLabel  = "这是一个关于矿工集群的标记测试文件用来测试字符编码检测是否正确工作"
+	traditionalLabel = "標記測試檔案用來測試字元編碼偵測是否正確運作這是一個關於礦工集群的標記"
+)
+
+// TestReaderAutoDetect locks in that auto-detection (an empty name)
+// correctly identifies each fixture's charset and decodes it to UTF-8,
+// rather than silently falling back to windows-1252 and mangling the
+// 标记/標記 column.
+func TestReaderAutoDetect(t *testing.T) {
+	cases := []struct {
+		file string
+		want string
+	}{
+		{"testdata/sample_utf8.csv", simplifiedLabel},
+		{"testdata/sample_gbk.csv", simplifiedLabel},
+		{"testdata/sample_gb18030.csv", simplifiedLabel},
+		{"testdata/sample_big5.csv", traditionalLabel},
+	}
+
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			f, err := os.Open(c.file)
+			if err != nil {
+				t.Fatalf("opening fixture: %v", err)
+			}
+			defer f.Close()
+
+			r, err := Reader(f, "")
+			if err != nil {
+				t.Fatalf("Reader: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("reading decoded output: %v", err)
+			}
+			if !strings.Contains(string(got), c.want) {
+				t.Errorf("decoded output does not contain %q; got %q", c.want, got)
+			}
+		})
+	}
+}
+
+// TestReaderExplicitEncoding locks in that a named encoding still
+// decodes correctly without relying on detection.
+func TestReaderExplicitEncoding(t *testing.T) {
+	cases := []struct {
+		file string
+		name string
+		want string
+	}{
+		{"testdata/sample_gbk.csv", "gbk", simplifiedLabel},
+		{"testdata/sample_gb18030.csv", "gb18030", simplifiedLabel},
+		{"testdata/sample_big5.csv", "big5", traditionalLabel},
+		{"testdata/sample_utf8.csv", "utf-8", simplifiedLabel},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := os.Open(c.file)
+			if err != nil {
+				t.Fatalf("opening fixture: %v", err)
+			}
+			defer f.Close()
+
+			r, err := Reader(f, c.name)
+			if err != nil {
+				t.Fatalf("Reader: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("reading decoded output: %v", err)
+			}
+			if !strings.Contains(string(got), c.want) {
+				t.Errorf("decoded output does not contain %q; got %q", c.want, got)
+			}
+		})
+	}
+}
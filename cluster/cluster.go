@@ -0,0 +1,89 @@
+// Package cluster loads the operator-maintained cluster metadata file,
+// keyed by prover address.
+package cluster
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Kunkka1103/prover-rank-fetcher/charset"
+	"github.com/gocarina/gocsv"
+)
+
+// Cluster is one row of operator-supplied metadata about a prover
+// address.
+type Cluster struct {
+	Address string `csv:"address"`
+	Label   string `csv:"label"`
+	Owner   string `csv:"owner"`
+	Region  string `csv:"region"`
+	Contact string `csv:"contact"`
+	Notes   string `csv:"notes"`
+}
+
+// Load reads the cluster file and returns it keyed by address. A file
+// with exactly two columns is treated as the legacy "label,address"
+// format with no header; anything else is parsed as the richer
+// address/label/owner/region/contact/notes schema with gocsv.
+//
+// encodingOverride selects the file's charset ("utf-8", "gbk",
+// "gb18030", "big5"); an empty string sniffs it from the file itself,
+// which is enough for the GBK/Big5 files common in Chinese-language
+// ops workflows.
+func Load(path, encodingOverride string) (map[string]Cluster, error) {
+	raw, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer raw.Close()
+
+	decoded, err := charset.Reader(raw, encodingOverride)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %v", path, err)
+	}
+	data, err := io.ReadAll(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return map[string]Cluster{}, nil
+	}
+
+	if len(records[0]) == 2 {
+		return loadLegacy(records), nil
+	}
+
+	var clusters []Cluster
+	if err := gocsv.UnmarshalBytes(data, &clusters); err != nil {
+		return nil, err
+	}
+	return byAddress(clusters), nil
+}
+
+// loadLegacy handles the original headerless "label,address" CSV.
+func loadLegacy(records [][]string) map[string]Cluster {
+	clusters := make(map[string]Cluster, len(records))
+	for _, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		clusters[record[1]] = Cluster{Address: record[1], Label: record[0]}
+	}
+	return clusters
+}
+
+func byAddress(clusters []Cluster) map[string]Cluster {
+	out := make(map[string]Cluster, len(clusters))
+	for _, c := range clusters {
+		out[c.Address] = c
+	}
+	return out
+}
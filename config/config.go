@@ -0,0 +1,87 @@
+// Package config loads the YAML/JSON configuration used by `serve`:
+// SMTP settings and the subject/body templates for the emailed report.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SMTP holds the mail server connection details. Host/port/user/from are
+// expected in the config file; Password is almost always supplied via
+// the SMTP_PASSWORD env var instead so secrets don't end up on disk.
+type SMTP struct {
+	Host     string `yaml:"host" json:"host"`
+	Port     int    `yaml:"port" json:"port"`
+	User     string `yaml:"user" json:"user"`
+	Password string `yaml:"password" json:"password"`
+	From     string `yaml:"from" json:"from"`
+}
+
+// Mail describes who the report goes to and how the subject/body are
+// templated. Subject and Body are text/template strings evaluated with
+// a mailer.TemplateData value (Date, TopAddress, TopReward).
+type Mail struct {
+	To      []string `yaml:"to" json:"to"`
+	Cc      []string `yaml:"cc" json:"cc"`
+	Subject string   `yaml:"subject" json:"subject"`
+	Body    string   `yaml:"body" json:"body"`
+}
+
+// Config is the top-level `--config` document.
+type Config struct {
+	SMTP SMTP `yaml:"smtp" json:"smtp"`
+	Mail Mail `yaml:"mail" json:"mail"`
+}
+
+// Load reads a YAML or JSON config file (chosen by extension) and
+// applies env-var overrides for the fields that are usually secrets:
+// SMTP_HOST, SMTP_PORT, SMTP_USER, SMTP_PASSWORD, SMTP_FROM.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = json.Unmarshal(data, &cfg)
+	case ".yaml", ".yml", "":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		return nil, fmt.Errorf("unsupported config extension %q (want .yaml, .yml or .json)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %v", path, err)
+	}
+
+	applyEnvOverrides(&cfg)
+	return &cfg, nil
+}
+
+func applyEnvOverrides(cfg *Config) {
+	if v := os.Getenv("SMTP_HOST"); v != "" {
+		cfg.SMTP.Host = v
+	}
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		if port, err := strconv.Atoi(v); err == nil {
+			cfg.SMTP.Port = port
+		}
+	}
+	if v := os.Getenv("SMTP_USER"); v != "" {
+		cfg.SMTP.User = v
+	}
+	if v := os.Getenv("SMTP_PASSWORD"); v != "" {
+		cfg.SMTP.Password = v
+	}
+	if v := os.Getenv("SMTP_FROM"); v != "" {
+		cfg.SMTP.From = v
+	}
+}
@@ -0,0 +1,124 @@
+// Package mailer emails the generated report as an attachment, using
+// the SMTP settings and subject/body templates from config.Config.
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/Kunkka1103/prover-rank-fetcher/config"
+)
+
+// TemplateData is the set of variables available to the subject/body
+// templates, e.g. "Top address today: {{.TopAddress}} ({{.TopReward}})".
+type TemplateData struct {
+	Date       string
+	TopAddress string
+	TopReward  string
+}
+
+// Send renders cfg.Mail's subject/body against data and emails
+// attachmentPath as a single attachment to cfg.Mail.To (and Cc).
+func Send(cfg *config.Config, data TemplateData, attachmentPath string) error {
+	subject, err := render("subject", cfg.Mail.Subject, data)
+	if err != nil {
+		return fmt.Errorf("error rendering subject template: %v", err)
+	}
+	body, err := render("body", cfg.Mail.Body, data)
+	if err != nil {
+		return fmt.Errorf("error rendering body template: %v", err)
+	}
+
+	msg, err := buildMessage(cfg, subject, body, attachmentPath)
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", cfg.SMTP.Host, cfg.SMTP.Port)
+	auth := smtp.PlainAuth("", cfg.SMTP.User, cfg.SMTP.Password, cfg.SMTP.Host)
+	recipients := append(append([]string{}, cfg.Mail.To...), cfg.Mail.Cc...)
+
+	return smtp.SendMail(addr, auth, cfg.SMTP.From, recipients, msg)
+}
+
+func render(name, tmpl string, data TemplateData) (string, error) {
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// buildMessage assembles a minimal multipart/mixed MIME message: a
+// plain-text body part plus the report file base64-encoded as an
+// attachment.
+func buildMessage(cfg *config.Config, subject, body, attachmentPath string) ([]byte, error) {
+	attachment, err := os.ReadFile(attachmentPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading attachment: %v", err)
+	}
+
+	const boundary = "prover-rank-fetcher-boundary"
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", cfg.SMTP.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", joinAddresses(cfg.Mail.To))
+	if len(cfg.Mail.Cc) > 0 {
+		fmt.Fprintf(&buf, "Cc: %s\r\n", joinAddresses(cfg.Mail.Cc))
+	}
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	fmt.Fprintf(&buf, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&buf, "%s\r\n\r\n", body)
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	fmt.Fprintf(&buf, "Content-Type: application/octet-stream\r\n")
+	fmt.Fprintf(&buf, "Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(&buf, "Content-Disposition: attachment; filename=%q\r\n\r\n", filepath.Base(attachmentPath))
+	writeBase64Lines(&buf, attachment)
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// base64LineLength is the conventional MIME wrap width (RFC 2045 §6.8),
+// well under the 998-octet SMTP line limit that a single unbroken
+// attachment line would blow through.
+const base64LineLength = 76
+
+// writeBase64Lines base64-encodes data into buf, wrapped at
+// base64LineLength characters per CRLF-terminated line.
+func writeBase64Lines(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > base64LineLength {
+		buf.WriteString(encoded[:base64LineLength])
+		buf.WriteString("\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\r\n")
+}
+
+func joinAddresses(addrs []string) string {
+	out := ""
+	for i, a := range addrs {
+		if i > 0 {
+			out += ", "
+		}
+		out += a
+	}
+	return out
+}
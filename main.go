@@ -1,19 +1,22 @@
 package main
 
 import (
-	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/tealeg/xlsx/v3"
+	"github.com/Kunkka1103/prover-rank-fetcher/catalog"
+	"github.com/Kunkka1103/prover-rank-fetcher/cluster"
+	"github.com/Kunkka1103/prover-rank-fetcher/report"
+	"github.com/Kunkka1103/prover-rank-fetcher/store"
 	"github.com/urfave/cli/v2"
 )
 
@@ -61,30 +64,256 @@ func fetchProverRankList(apiURL string, startTime, endTime int64) ([]Prover, err
 	return data.Data, nil
 }
 
-func readClusterNames(filePath string) (map[string]string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+// buildRows converts the fetched provers into report rows, ranked in
+// fetch order and enriched with cluster metadata and estimated GPU
+// composition from cat.
+func buildRows(provers []Prover, clusters map[string]cluster.Cluster, cat *catalog.Catalog) []report.Row {
+	hasPricing := cat.HasPricing()
+	rows := make([]report.Row, len(provers))
+	for i, prover := range provers {
+		totalPuzzleCredits, _ := prover.TotalPuzzleCredits.Float64()
+		dailyPuzzleCredits, _ := prover.DailyPuzzleCredits.Float64()
+		networkSpeed, _ := prover.NetworkSpeed.Float64()
+
+		c := clusters[prover.Address]
+		gpuCounts := cat.Counts(networkSpeed)
+
+		row := report.Row{
+			Rank:            i + 1,
+			Label:           c.Label,
+			Address:         prover.Address,
+			TotalCredits:    totalPuzzleCredits,
+			TotalCreditsPct: prover.TotalPuzzleCreditsPercentage,
+			DailyCredits:    dailyPuzzleCredits,
+			DailyCreditsPct: prover.DailyPuzzleCreditsPercentage,
+			NetworkSpeedM:   networkSpeed / 1e6,
+			NetworkSpeedPct: prover.NetworkSpeedPercentage,
+			Owner:           c.Owner,
+			Region:          c.Region,
+			Contact:         c.Contact,
+			Notes:           c.Notes,
+			GPUCounts:       gpuCounts,
+		}
+		if hasPricing {
+			value := cat.EstimatedValue(gpuCounts)
+			row.EstHardwareValue = &value
+		}
+		rows[i] = row
 	}
-	defer file.Close()
+	return rows
+}
 
-	reader := csv.NewReader(file)
-	clusterMap := make(map[string]string)
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
+// gpuNames returns the catalog's GPU model names in config order, for
+// building report.GPUColumns.
+func gpuNames(cat *catalog.Catalog) []string {
+	names := make([]string, len(cat.GPUs))
+	for i, gpu := range cat.GPUs {
+		names[i] = gpu.Name
+	}
+	return names
+}
+
+// resolveCompareWindow turns a --compare-with shortcut into an explicit
+// previous window relative to the current one. "yesterday" shifts back
+// one day; "Nd" shifts back N days. Anything else is rejected so typos
+// fail fast instead of silently comparing against the wrong window.
+func resolveCompareWindow(shortcut string, startTime, endTime int64) (prevStart, prevEnd int64, err error) {
+	var shift int64
+	switch {
+	case shortcut == "yesterday":
+		shift = 86400
+	case strings.HasSuffix(shortcut, "d"):
+		days, convErr := strconv.Atoi(strings.TrimSuffix(shortcut, "d"))
+		if convErr != nil {
+			return 0, 0, fmt.Errorf("invalid --compare-with shortcut %q: %v", shortcut, convErr)
 		}
-		if len(record) < 2 {
+		shift = int64(days) * 86400
+	default:
+		return 0, 0, fmt.Errorf("unrecognized --compare-with shortcut %q (want \"yesterday\" or \"Nd\")", shortcut)
+	}
+	return startTime - shift, endTime - shift, nil
+}
+
+// applyComparison fills in the Prev* fields on rows from the snapshots
+// stored for the previous window. Rank movement is derived by ranking
+// the previous snapshots by total credits, the same ordering the API
+// already returns rows in.
+func applyComparison(rows []report.Row, prev map[string]store.Snapshot) []report.Row {
+	prevRanked := make([]store.Snapshot, 0, len(prev))
+	for _, snap := range prev {
+		prevRanked = append(prevRanked, snap)
+	}
+	sort.Slice(prevRanked, func(i, j int) bool {
+		return prevRanked[i].TotalPuzzleCredits > prevRanked[j].TotalPuzzleCredits
+	})
+	prevRank := make(map[string]int, len(prevRanked))
+	for i, snap := range prevRanked {
+		prevRank[snap.Address] = i + 1
+	}
+
+	for i, row := range rows {
+		snap, ok := prev[row.Address]
+		if !ok {
 			continue
 		}
-		clusterMap[record[1]] = record[0] // Assuming the first column is name and second column is address
+		rank := prevRank[row.Address]
+		rows[i].PrevRank = &rank
+		rows[i].PrevTotalCredits = floatPtr(snap.TotalPuzzleCredits)
+		rows[i].PrevDailyCredits = floatPtr(snap.DailyPuzzleCredits)
+		rows[i].PrevNetworkSpeedM = floatPtr(snap.NetworkSpeed / 1e6)
+	}
+	return rows
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+// toSnapshots converts report rows plus the fetch window into the
+// records persisted by --store.
+func toSnapshots(rows []report.Row, startTime, endTime int64, fetchedAt time.Time) []store.Snapshot {
+	snapshots := make([]store.Snapshot, len(rows))
+	for i, row := range rows {
+		snapshots[i] = store.Snapshot{
+			Address:                      row.Address,
+			StartTime:                    startTime,
+			EndTime:                      endTime,
+			Label:                        row.Label,
+			TotalPuzzleCredits:           row.TotalCredits,
+			TotalPuzzleCreditsPercentage: row.TotalCreditsPct,
+			DailyPuzzleCredits:           row.DailyCredits,
+			DailyPuzzleCreditsPercentage: row.DailyCreditsPct,
+			NetworkSpeed:                 row.NetworkSpeedM * 1e6,
+			NetworkSpeedPercentage:       row.NetworkSpeedPct,
+			FetchedAt:                    fetchedAt,
+		}
+	}
+	return snapshots
+}
+
+// snapshotRows adapts stored snapshots into the minimal []report.Row
+// shape report.Summarize needs, for computing the previous window's
+// per-cluster daily-reward shares.
+func snapshotRows(snapshots map[string]store.Snapshot) []report.Row {
+	rows := make([]report.Row, 0, len(snapshots))
+	for _, snap := range snapshots {
+		rows = append(rows, report.Row{
+			Label:         snap.Label,
+			TotalCredits:  snap.TotalPuzzleCredits,
+			DailyCredits:  snap.DailyPuzzleCredits,
+			NetworkSpeedM: snap.NetworkSpeed / 1e6,
+		})
+	}
+	return rows
+}
+
+// sheetSet turns --sheets into a lookup set.
+func sheetSet(sheets []string) map[string]bool {
+	want := make(map[string]bool, len(sheets))
+	for _, s := range sheets {
+		want[s] = true
+	}
+	return want
+}
+
+// wantDetailSheet reports whether the detail sheet (the per-address
+// rows) should be written. It's skipped only when the caller explicitly
+// asked for other sheets without "detail"; an empty or detail-only
+// --sheets still gets it. The detail sheet itself always exists, so
+// --sheets=alerts without a comparison window saves it empty.
+func wantDetailSheet(want map[string]bool) bool {
+	if want["detail"] || len(want) == 0 {
+		return true
+	}
+	return !(want["cluster"] || want["overview"] || want["alerts"])
+}
+
+// xlsxSheetsHook builds the addSheets callback for renderReport: it
+// appends whichever of the cluster/overview/alerts sheets --sheets
+// asked for, after the detail sheet. "detail" itself needs no action
+// here since Header/Row already populated it.
+func xlsxSheetsHook(rows []report.Row, cat *catalog.Catalog, want map[string]bool, prevClusterShares map[string]float64, alertThreshold float64) func(*report.XLSXRenderer) error {
+	return func(xr *report.XLSXRenderer) error {
+		summaries := report.Summarize(rows)
+
+		if want["cluster"] {
+			if err := xr.AddClusterSheet(summaries, gpuNames(cat)); err != nil {
+				return fmt.Errorf("error adding 按标记汇总 sheet: %v", err)
+			}
+		}
+		if want["overview"] {
+			if err := xr.AddOverviewSheet(report.Overview(rows)); err != nil {
+				return fmt.Errorf("error adding 全网概览 sheet: %v", err)
+			}
+		}
+		if want["alerts"] {
+			if prevClusterShares == nil {
+				log.Println("skipping 告警 sheet: --compare-with (or --prev-start/--prev-end) is required")
+			} else {
+				alerts := report.Alerts(summaries, prevClusterShares, alertThreshold)
+				if err := xr.AddAlertSheet(alerts); err != nil {
+					return fmt.Errorf("error adding 告警 sheet: %v", err)
+				}
+			}
+		}
+		return nil
 	}
+}
 
-	return clusterMap, nil
+// newRenderer builds the Renderer for a given --format value.
+func newRenderer(format string) (report.Renderer, error) {
+	switch format {
+	case "xlsx":
+		return report.NewXLSXRenderer("Sheet1")
+	case "csv":
+		return report.NewCSVRenderer(), nil
+	case "json":
+		return report.NewJSONRenderer(), nil
+	case "table":
+		return report.NewTableRenderer(), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want xlsx, csv, json or table)", format)
+	}
+}
+
+// renderReport writes rows through renderer r, then runs addSheets (if
+// set) before closing it, so xlsx can append its summary sheets after
+// the detail rows but before the file is saved. writeDetail is always
+// true outside xlsx; for xlsx it follows --sheets so `--sheets=overview`
+// doesn't emit the per-address rows too. The table format prints
+// straight to stdout; every other format is written to a file under
+// outputDir named <baseName>.<format>.
+func renderReport(r report.Renderer, cols []report.Column, rows []report.Row, format, outputDir, baseName string, writeDetail bool, addSheets func(*report.XLSXRenderer) error) error {
+	if writeDetail {
+		r.Header(report.Headers(cols))
+		for _, row := range rows {
+			r.Row(report.Cells(cols, row))
+		}
+	}
+
+	if addSheets != nil {
+		if xr, ok := r.(*report.XLSXRenderer); ok {
+			if err := addSheets(xr); err != nil {
+				return err
+			}
+		}
+	}
+
+	if format == "table" {
+		return r.Close(os.Stdout)
+	}
+
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("%s.%s", baseName, format))
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %v", outputPath, err)
+	}
+	defer out.Close()
+
+	if err := r.Close(out); err != nil {
+		return fmt.Errorf("error writing %s: %v", outputPath, err)
+	}
+
+	fmt.Printf("数据已保存到 %s\n", outputPath)
+	return nil
 }
 
 func parseDateTime(dateTimeStr string) (int64, error) {
@@ -117,15 +346,59 @@ func main() {
 			},
 			&cli.StringFlag{
 				Name:     "cluster_file",
-				Usage:    "Path to the cluster-name file",
+				Usage:    "Path to the cluster-name file (legacy label,address CSV, or the richer address/label/owner/region/contact/notes schema)",
 				Required: true,
 			},
+			&cli.StringFlag{
+				Name:  "catalog",
+				Usage: "Path to a YAML GPU hashrate catalog. Unset falls back to the built-in 3080/4090 table.",
+			},
+			&cli.StringFlag{
+				Name:  "cluster-encoding",
+				Usage: "Charset of --cluster_file: utf-8, gbk, gb18030 or big5. Unset auto-detects it.",
+			},
 			&cli.StringFlag{
 				Name:     "api_url",
 				Usage:    "API URL for fetching prover rank list",
 				Value:    "http://localhost:8088/api/v1/provers/prover_rank_list",
 				Required: false,
 			},
+			&cli.StringSliceFlag{
+				Name:  "format",
+				Usage: "Output format(s) to generate: xlsx, csv, json, table (repeatable)",
+				Value: cli.NewStringSlice("xlsx"),
+			},
+			&cli.StringFlag{
+				Name:  "output-dir",
+				Usage: "Directory to write file-based output formats into",
+				Value: ".",
+			},
+			&cli.StringFlag{
+				Name:  "store",
+				Usage: "DSN to persist this fetch into (sqlite file path, or mysql://... / postgres://...). Unset disables persistence.",
+			},
+			&cli.StringFlag{
+				Name:  "compare-with",
+				Usage: `Previous window to diff against: "yesterday", "7d", or set --prev-start/--prev-end instead. Requires --store.`,
+			},
+			&cli.StringFlag{
+				Name:  "prev-start",
+				Usage: "Explicit previous-window start (YYYY-MM-DD HH:MM:SS), used instead of --compare-with's shortcuts",
+			},
+			&cli.StringFlag{
+				Name:  "prev-end",
+				Usage: "Explicit previous-window end (YYYY-MM-DD HH:MM:SS), used instead of --compare-with's shortcuts",
+			},
+			&cli.StringSliceFlag{
+				Name:  "sheets",
+				Usage: "xlsx sheets to generate: detail, cluster, overview, alerts (repeatable)",
+				Value: cli.NewStringSlice("detail", "cluster", "overview", "alerts"),
+			},
+			&cli.Float64Flag{
+				Name:  "alert-threshold",
+				Usage: "Percentage-point drop in a cluster's daily-reward share that triggers an alerts-sheet row. Requires --compare-with.",
+				Value: 5,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			startDateTime := c.String("start_datetime")
@@ -151,67 +424,149 @@ func main() {
 				return fmt.Errorf("error fetching prover rank list: %v", err)
 			}
 
-			clusterNames, err := readClusterNames(clusterFilePath)
+			clusters, err := cluster.Load(clusterFilePath, c.String("cluster-encoding"))
 			if err != nil {
-				return fmt.Errorf("error reading cluster names: %v", err)
+				return fmt.Errorf("error reading cluster file: %v", err)
 			}
 
-			file := xlsx.NewFile()
-			sheet, err := file.AddSheet("Sheet1")
-			if err != nil {
-				return fmt.Errorf("error creating sheet: %v", err)
+			cat := catalog.Default()
+			if catalogPath := c.String("catalog"); catalogPath != "" {
+				cat, err = catalog.Load(catalogPath)
+				if err != nil {
+					return fmt.Errorf("error loading --catalog: %v", err)
+				}
 			}
 
-			headerStyle := xlsx.NewStyle()
-			headerFont := xlsx.NewFont(12, "Calibri")
-			headerFont.Bold = true
-			headerStyle.Font = *headerFont
-
-			header := []string{"排名", "标记", "地址", "累计出块奖励(Puzzle Credits)", "占全网比例", "昨日奖励", "单日奖励占比", "节点速率(M s/s)", "速率占比", "GPU数量/3080", "GPU数量/4090"}
-			row := sheet.AddRow()
-			for _, h := range header {
-				cell := row.AddCell()
-				cell.Value = h
-				cell.SetStyle(headerStyle)
+			rows := buildRows(provers, clusters, cat)
+
+			var db *store.Store
+			if dsn := c.String("store"); dsn != "" {
+				db, err = store.Open(dsn)
+				if err != nil {
+					return fmt.Errorf("error opening --store: %v", err)
+				}
+				if err := db.Upsert(toSnapshots(rows, startTime, endTime, time.Now())); err != nil {
+					return fmt.Errorf("error persisting snapshot: %v", err)
+				}
 			}
 
-			for i, prover := range provers {
-				row := sheet.AddRow()
-				row.AddCell().Value = strconv.Itoa(i + 1) // 排名
-				row.AddCell().Value = clusterNames[prover.Address] // 标记
-				row.AddCell().Value = prover.Address // 地址
-
-				totalPuzzleCredits, _ := prover.TotalPuzzleCredits.Float64()
-				row.AddCell().SetFloatWithFormat(totalPuzzleCredits, "0.00") // 累计出块奖励(Puzzle Credits)
+			cols := append(append([]report.Column{}, report.Columns...), report.GPUColumns(gpuNames(cat), cat.HasPricing())...)
+			var prevClusterShares map[string]float64
+			if compareWith := c.String("compare-with"); compareWith != "" || (c.String("prev-start") != "" && c.String("prev-end") != "") {
+				if db == nil {
+					return fmt.Errorf("--compare-with requires --store")
+				}
+
+				var prevStart, prevEnd int64
+				if compareWith != "" {
+					prevStart, prevEnd, err = resolveCompareWindow(compareWith, startTime, endTime)
+				} else {
+					prevStart, err = parseDateTime(c.String("prev-start"))
+					if err == nil {
+						prevEnd, err = parseDateTime(c.String("prev-end"))
+					}
+				}
+				if err != nil {
+					return fmt.Errorf("error resolving comparison window: %v", err)
+				}
+
+				prevSnapshots, err := db.Window(prevStart, prevEnd)
+				if err != nil {
+					return fmt.Errorf("error loading comparison window: %v", err)
+				}
+
+				rows = applyComparison(rows, prevSnapshots)
+				cols = append(cols, report.CompareColumns()...)
+				prevClusterShares = report.ClusterDailySharesOf(report.Summarize(snapshotRows(prevSnapshots)))
+			}
 
-				row.AddCell().Value = prover.TotalPuzzleCreditsPercentage // 占全网比例
+			want := sheetSet(c.StringSlice("sheets"))
+			alertThreshold := c.Float64("alert-threshold")
+
+			formats := c.StringSlice("format")
+			outputDir := c.String("output-dir")
+			baseName := fmt.Sprintf("aleo大矿工统计-%s", time.Now().Format("2006-01-02"))
+
+			for _, format := range formats {
+				renderer, err := newRenderer(format)
+				if err != nil {
+					return err
+				}
+
+				writeDetail := true
+				var addSheets func(*report.XLSXRenderer) error
+				if format == "xlsx" {
+					writeDetail = wantDetailSheet(want)
+					addSheets = xlsxSheetsHook(rows, cat, want, prevClusterShares, alertThreshold)
+				}
+
+				if err := renderReport(renderer, cols, rows, format, outputDir, baseName, writeDetail, addSheets); err != nil {
+					return err
+				}
+			}
 
-				dailyPuzzleCredits, _ := prover.DailyPuzzleCredits.Float64()
-				row.AddCell().SetFloatWithFormat(dailyPuzzleCredits, "0.00") // 昨日奖励
+			return nil
+		},
+		Commands: []*cli.Command{
+			trendCommand(),
+			serveCommand(),
+		},
+	}
 
-				row.AddCell().Value = prover.DailyPuzzleCreditsPercentage // 单日奖励占比
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
 
-				networkSpeed, _ := prover.NetworkSpeed.Float64()
-				row.AddCell().SetFloatWithFormat(networkSpeed / 1e6, "0.00") // 节点速率(M s/s)
+// trendCommand implements `prover-rank-fetcher trend`, dumping a
+// per-address time series from a previously populated --store.
+func trendCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "trend",
+		Usage: "Print the stored time series for an address or cluster over a window",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "store", Usage: "DSN to read snapshots from", Required: true},
+			&cli.StringFlag{Name: "address", Usage: "Prover address to trend (mutually exclusive with --cluster)"},
+			&cli.StringFlag{Name: "cluster", Usage: "Cluster label to trend (mutually exclusive with --address)"},
+			&cli.StringFlag{Name: "start_datetime", Usage: "Start of the trend window (YYYY-MM-DD HH:MM:SS)", Required: true},
+			&cli.StringFlag{Name: "end_datetime", Usage: "End of the trend window (YYYY-MM-DD HH:MM:SS)", Required: true},
+		},
+		Action: func(c *cli.Context) error {
+			address := c.String("address")
+			cluster := c.String("cluster")
+			if address == "" && cluster == "" {
+				return fmt.Errorf("trend requires --address or --cluster")
+			}
+			if address != "" && cluster != "" {
+				return fmt.Errorf("trend takes --address or --cluster, not both")
+			}
 
-				row.AddCell().Value = prover.NetworkSpeedPercentage // 速率占比
+			startTime, err := parseDateTime(c.String("start_datetime"))
+			if err != nil {
+				return fmt.Errorf("error parsing start date and time: %v", err)
+			}
+			endTime, err := parseDateTime(c.String("end_datetime"))
+			if err != nil {
+				return fmt.Errorf("error parsing end date and time: %v", err)
+			}
 
-				row.AddCell().SetInt(int(networkSpeed / 15000)) // GPU数量/3080
-				row.AddCell().SetInt(int(networkSpeed / 43000)) // GPU数量/4090
+			db, err := store.Open(c.String("store"))
+			if err != nil {
+				return fmt.Errorf("error opening --store: %v", err)
 			}
 
-			today := time.Now().Format("2006-01-02")
-			outputFileName := fmt.Sprintf("aleo大矿工统计-%s.xlsx", today)
-			if err := file.Save(outputFileName); err != nil {
-				return fmt.Errorf("error saving file: %v", err)
+			snapshots, err := db.Trend(address, cluster, startTime, endTime)
+			if err != nil {
+				return fmt.Errorf("error loading trend: %v", err)
 			}
 
-			fmt.Printf("数据已保存到 %s\n", outputFileName)
+			fmt.Printf("%-20s %-42s %-12s %-12s %-12s\n", "window start", "address", "total", "daily", "speed(M/s)")
+			for _, snap := range snapshots {
+				fmt.Printf("%-20s %-42s %-12.2f %-12.2f %-12.2f\n",
+					time.Unix(snap.StartTime, 0).Format("2006-01-02 15:04:05"),
+					snap.Address, snap.TotalPuzzleCredits, snap.DailyPuzzleCredits, snap.NetworkSpeed/1e6)
+			}
 			return nil
 		},
 	}
-
-	if err := app.Run(os.Args); err != nil {
-		log.Fatal(err)
-	}
 }
@@ -0,0 +1,175 @@
+package report
+
+import "sort"
+
+// ClusterSummary rolls up every row sharing a Label into one entry for
+// the 按标记汇总 sheet.
+type ClusterSummary struct {
+	Label         string
+	TotalCredits  float64
+	DailyCredits  float64
+	NetworkSpeedM float64
+	GPUCounts     map[string]int
+	CreditShare   float64 // this cluster's share of total credits, in percent
+	SpeedShare    float64 // this cluster's share of total network speed, in percent
+}
+
+// Summarize groups rows by Label (blank labels become "未标记") and
+// sums their metrics, sorted by total credits descending.
+func Summarize(rows []Row) []ClusterSummary {
+	byLabel := make(map[string]*ClusterSummary)
+	var order []string
+	var totalCredits, totalSpeed float64
+
+	for _, r := range rows {
+		label := r.Label
+		if label == "" {
+			label = "未标记"
+		}
+		s, ok := byLabel[label]
+		if !ok {
+			s = &ClusterSummary{Label: label, GPUCounts: map[string]int{}}
+			byLabel[label] = s
+			order = append(order, label)
+		}
+		s.TotalCredits += r.TotalCredits
+		s.DailyCredits += r.DailyCredits
+		s.NetworkSpeedM += r.NetworkSpeedM
+		for name, count := range r.GPUCounts {
+			s.GPUCounts[name] += count
+		}
+		totalCredits += r.TotalCredits
+		totalSpeed += r.NetworkSpeedM
+	}
+
+	summaries := make([]ClusterSummary, 0, len(order))
+	for _, label := range order {
+		s := *byLabel[label]
+		if totalCredits > 0 {
+			s.CreditShare = s.TotalCredits / totalCredits * 100
+		}
+		if totalSpeed > 0 {
+			s.SpeedShare = s.NetworkSpeedM / totalSpeed * 100
+		}
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].TotalCredits > summaries[j].TotalCredits
+	})
+	return summaries
+}
+
+// NetworkOverview is the single row of network-wide totals shown on
+// the 全网概览 sheet.
+type NetworkOverview struct {
+	TotalCredits    float64
+	TotalSpeedM     float64
+	ActiveAddresses int
+	Top10Share      float64 // top 10 addresses' share of total credits, in percent
+	Gini            float64 // Gini coefficient of NetworkSpeed, 0 (equal) to 1 (concentrated)
+}
+
+// Overview computes network-wide totals and the Gini coefficient from
+// rows.
+func Overview(rows []Row) NetworkOverview {
+	var o NetworkOverview
+	o.ActiveAddresses = len(rows)
+
+	speeds := make([]float64, len(rows))
+	for i, r := range rows {
+		o.TotalCredits += r.TotalCredits
+		o.TotalSpeedM += r.NetworkSpeedM
+		speeds[i] = r.NetworkSpeedM
+	}
+
+	byCredits := append([]Row{}, rows...)
+	sort.Slice(byCredits, func(i, j int) bool { return byCredits[i].TotalCredits > byCredits[j].TotalCredits })
+	var top10 float64
+	for i := 0; i < len(byCredits) && i < 10; i++ {
+		top10 += byCredits[i].TotalCredits
+	}
+	if o.TotalCredits > 0 {
+		o.Top10Share = top10 / o.TotalCredits * 100
+	}
+
+	o.Gini = gini(speeds)
+	return o
+}
+
+// gini computes the Gini coefficient of values as a decentralization
+// indicator: sort ascending, G = (2*Σ i*x_i)/(n*Σx_i) - (n+1)/n.
+func gini(values []float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	var weightedSum, total float64
+	for i, x := range sorted {
+		weightedSum += float64(i+1) * x
+		total += x
+	}
+	if total == 0 {
+		return 0
+	}
+	return (2*weightedSum)/(float64(n)*total) - float64(n+1)/float64(n)
+}
+
+// Alert is one cluster whose daily-reward share dropped by more than
+// the configured threshold versus a previous snapshot.
+type Alert struct {
+	Label        string
+	PrevShare    float64
+	CurrentShare float64
+	DropPct      float64 // PrevShare - CurrentShare, in percentage points
+}
+
+// Alerts compares each cluster's current daily-credit share against
+// prevShares (label -> share%) and returns every cluster whose share
+// dropped by more than thresholdPct, worst drop first. Clusters absent
+// from prevShares (no prior snapshot) are skipped rather than flagged.
+func Alerts(current []ClusterSummary, prevShares map[string]float64, thresholdPct float64) []Alert {
+	var totalDaily float64
+	for _, s := range current {
+		totalDaily += s.DailyCredits
+	}
+	if totalDaily == 0 {
+		return nil
+	}
+
+	var alerts []Alert
+	for _, s := range current {
+		prevShare, ok := prevShares[s.Label]
+		if !ok {
+			continue
+		}
+		currentShare := s.DailyCredits / totalDaily * 100
+		drop := prevShare - currentShare
+		if drop > thresholdPct {
+			alerts = append(alerts, Alert{Label: s.Label, PrevShare: prevShare, CurrentShare: currentShare, DropPct: drop})
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].DropPct > alerts[j].DropPct })
+	return alerts
+}
+
+// ClusterDailySharesOf computes each label's share of total daily
+// credits from a prior set of rows, for feeding into Alerts as
+// prevShares.
+func ClusterDailySharesOf(summaries []ClusterSummary) map[string]float64 {
+	var total float64
+	for _, s := range summaries {
+		total += s.DailyCredits
+	}
+	shares := make(map[string]float64, len(summaries))
+	if total == 0 {
+		return shares
+	}
+	for _, s := range summaries {
+		shares[s.Label] = s.DailyCredits / total * 100
+	}
+	return shares
+}
@@ -0,0 +1,146 @@
+package report
+
+import "strconv"
+
+// Row holds one prover's data in the typed form every renderer works
+// from. Callers build a []Row from the fetched provers and cluster
+// labels, then feed it through Columns to produce []Cell per row.
+type Row struct {
+	Rank            int
+	Label           string
+	Address         string
+	TotalCredits    float64
+	TotalCreditsPct string
+	DailyCredits    float64
+	DailyCreditsPct string
+	NetworkSpeedM   float64
+	NetworkSpeedPct string
+
+	// Owner, Region, Contact and Notes come from the richer cluster
+	// metadata schema; they're blank when the cluster file is in the
+	// legacy label,address format.
+	Owner   string
+	Region  string
+	Contact string
+	Notes   string
+
+	// GPUCounts and EstHardwareValue are populated from the --catalog
+	// GPU table, keyed by GPU model name. EstHardwareValue is nil
+	// unless the catalog has at least one GPU with a price set.
+	GPUCounts        map[string]int
+	EstHardwareValue *float64
+
+	// Prev* are only set in --compare-with mode, holding the matching
+	// address's values from the comparison window. A nil PrevRank means
+	// the address wasn't present in that window at all.
+	PrevRank          *int
+	PrevTotalCredits  *float64
+	PrevDailyCredits  *float64
+	PrevNetworkSpeedM *float64
+}
+
+// Column is one column shared by every renderer: its header text, its
+// preferred alignment, and how to pull the cell value out of a Row.
+type Column struct {
+	Header string
+	Align  Align
+	Value  func(Row) Cell
+}
+
+// Columns is the single source of truth for report layout. Add or
+// reorder a column here and the xlsx, csv, json and table renderers all
+// stay in sync.
+var Columns = []Column{
+	{"排名", AlignRight, func(r Row) Cell {
+		return Cell{strconv.Itoa(r.Rank), r.Rank}
+	}},
+	{"标记", AlignLeft, func(r Row) Cell {
+		return Cell{r.Label, r.Label}
+	}},
+	{"地址", AlignLeft, func(r Row) Cell {
+		return Cell{r.Address, r.Address}
+	}},
+	{"累计出块奖励(Puzzle Credits)", AlignRight, func(r Row) Cell {
+		return Cell{formatFloat(r.TotalCredits), r.TotalCredits}
+	}},
+	{"占全网比例", AlignRight, func(r Row) Cell {
+		return Cell{r.TotalCreditsPct, r.TotalCreditsPct}
+	}},
+	{"昨日奖励", AlignRight, func(r Row) Cell {
+		return Cell{formatFloat(r.DailyCredits), r.DailyCredits}
+	}},
+	{"单日奖励占比", AlignRight, func(r Row) Cell {
+		return Cell{r.DailyCreditsPct, r.DailyCreditsPct}
+	}},
+	{"节点速率(M s/s)", AlignRight, func(r Row) Cell {
+		return Cell{formatFloat(r.NetworkSpeedM), r.NetworkSpeedM}
+	}},
+	{"速率占比", AlignRight, func(r Row) Cell {
+		return Cell{r.NetworkSpeedPct, r.NetworkSpeedPct}
+	}},
+	{"负责人", AlignLeft, func(r Row) Cell {
+		return Cell{r.Owner, r.Owner}
+	}},
+	{"地区", AlignLeft, func(r Row) Cell {
+		return Cell{r.Region, r.Region}
+	}},
+	{"联系方式", AlignLeft, func(r Row) Cell {
+		return Cell{r.Contact, r.Contact}
+	}},
+	{"备注", AlignLeft, func(r Row) Cell {
+		return Cell{r.Notes, r.Notes}
+	}},
+}
+
+// GPUColumns builds one "GPU数量/<name>" column per catalog GPU model,
+// plus an "预估硬件价值(USD)" column when any model has a price, so the
+// catalog file fully controls this part of the report layout.
+func GPUColumns(gpuNames []string, hasPricing bool) []Column {
+	cols := make([]Column, 0, len(gpuNames)+1)
+	for _, name := range gpuNames {
+		name := name
+		cols = append(cols, Column{
+			Header: "GPU数量/" + name,
+			Align:  AlignRight,
+			Value: func(r Row) Cell {
+				count := r.GPUCounts[name]
+				return Cell{strconv.Itoa(count), count}
+			},
+		})
+	}
+	if hasPricing {
+		cols = append(cols, Column{
+			Header: "预估硬件价值(USD)",
+			Align:  AlignRight,
+			Value: func(r Row) Cell {
+				if r.EstHardwareValue == nil {
+					return Cell{"", nil}
+				}
+				return Cell{formatFloat(*r.EstHardwareValue), *r.EstHardwareValue}
+			},
+		})
+	}
+	return cols
+}
+
+// Headers returns the titles of cols in order, ready for Renderer.Header.
+func Headers(cols []Column) []string {
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = c.Header
+	}
+	return headers
+}
+
+// Cells renders r through cols, in order.
+func Cells(cols []Column, r Row) []Cell {
+	cells := make([]Cell, len(cols))
+	for i, c := range cols {
+		cells[i] = c.Value(r)
+	}
+	return cells
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
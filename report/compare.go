@@ -0,0 +1,71 @@
+package report
+
+import "fmt"
+
+// CompareColumns returns the extra delta/delta%/movement columns added
+// to a report when run with --compare-with. They're appended to the
+// base Columns rather than replacing them, so detail rows still show
+// the current window's values first.
+func CompareColumns() []Column {
+	return []Column{
+		deltaColumn("累计出块奖励 环比", func(r Row) (float64, *float64) { return r.TotalCredits, r.PrevTotalCredits }),
+		deltaPctColumn("累计出块奖励 环比%", func(r Row) (float64, *float64) { return r.TotalCredits, r.PrevTotalCredits }),
+		deltaColumn("昨日奖励 环比", func(r Row) (float64, *float64) { return r.DailyCredits, r.PrevDailyCredits }),
+		deltaPctColumn("昨日奖励 环比%", func(r Row) (float64, *float64) { return r.DailyCredits, r.PrevDailyCredits }),
+		deltaColumn("节点速率 环比", func(r Row) (float64, *float64) { return r.NetworkSpeedM, r.PrevNetworkSpeedM }),
+		deltaPctColumn("节点速率 环比%", func(r Row) (float64, *float64) { return r.NetworkSpeedM, r.PrevNetworkSpeedM }),
+		{
+			Header: "排名变动",
+			Align:  AlignRight,
+			Value: func(r Row) Cell {
+				if r.PrevRank == nil {
+					return Cell{"new", "new"}
+				}
+				movement := *r.PrevRank - r.Rank // positive means the rank improved (moved up)
+				arrow := rankArrow(movement)
+				return Cell{arrow, arrow}
+			},
+		},
+	}
+}
+
+func deltaColumn(header string, values func(Row) (current float64, prev *float64)) Column {
+	return Column{
+		Header: header,
+		Align:  AlignRight,
+		Value: func(r Row) Cell {
+			current, prev := values(r)
+			if prev == nil {
+				return Cell{"new", nil}
+			}
+			delta := current - *prev
+			return Cell{formatFloat(delta), delta}
+		},
+	}
+}
+
+func deltaPctColumn(header string, values func(Row) (current float64, prev *float64)) Column {
+	return Column{
+		Header: header,
+		Align:  AlignRight,
+		Value: func(r Row) Cell {
+			current, prev := values(r)
+			if prev == nil || *prev == 0 {
+				return Cell{"new", nil}
+			}
+			deltaPct := (current - *prev) / *prev * 100
+			return Cell{fmt.Sprintf("%.2f%%", deltaPct), deltaPct}
+		},
+	}
+}
+
+func rankArrow(movement int) string {
+	switch {
+	case movement > 0:
+		return fmt.Sprintf("↑%d", movement)
+	case movement < 0:
+		return fmt.Sprintf("↓%d", -movement)
+	default:
+		return "="
+	}
+}
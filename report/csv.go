@@ -0,0 +1,36 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVRenderer buffers rows and writes them out as UTF-8 CSV on Close.
+type CSVRenderer struct {
+	records [][]string
+}
+
+func NewCSVRenderer() *CSVRenderer {
+	return &CSVRenderer{}
+}
+
+func (c *CSVRenderer) Header(cols []string) {
+	c.records = append(c.records, cols)
+}
+
+func (c *CSVRenderer) Row(cells []Cell) {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = cell.Display
+	}
+	c.records = append(c.records, record)
+}
+
+func (c *CSVRenderer) Close(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(c.records); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
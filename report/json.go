@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// JSONRenderer emits rows as a JSON array of objects keyed by header,
+// using each cell's Raw value so numbers and strings stay their own
+// type instead of being flattened to display strings.
+type JSONRenderer struct {
+	headers []string
+	rows    []jsonRow
+}
+
+// jsonRow is a single rendered row, keeping columns in header order so
+// MarshalJSON doesn't fall back to Go's alphabetical map-key sort.
+type jsonRow struct {
+	headers []string
+	values  []interface{}
+}
+
+func (r jsonRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, h := range r.headers {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(h)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(r.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func NewJSONRenderer() *JSONRenderer {
+	return &JSONRenderer{}
+}
+
+func (j *JSONRenderer) Header(cols []string) {
+	j.headers = cols
+}
+
+func (j *JSONRenderer) Row(cells []Cell) {
+	n := len(cells)
+	if n > len(j.headers) {
+		n = len(j.headers)
+	}
+	row := jsonRow{headers: j.headers[:n], values: make([]interface{}, n)}
+	for i := 0; i < n; i++ {
+		row.values[i] = cells[i].Raw
+	}
+	j.rows = append(j.rows, row)
+}
+
+func (j *JSONRenderer) Close(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(j.rows)
+}
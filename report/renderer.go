@@ -0,0 +1,35 @@
+// Package report turns fetched prover rows into output files or terminal
+// output. All four renderers (xlsx, csv, json, table) share the same Row
+// data and Columns definition so adding or re-ordering a column only
+// requires touching columns.go.
+package report
+
+import "io"
+
+// Align records a column's preferred justification. No renderer reads
+// it: the terminal table left-aligns every column.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+)
+
+// Cell is a single rendered value. Display is what gets written to csv,
+// the terminal table, and xlsx for non-numeric cells. Raw carries the
+// original typed value (float64, int, string, ...) so the json renderer
+// can emit real numbers instead of stringified cells, and xlsx can write
+// float64/int values as numeric cells.
+type Cell struct {
+	Display string
+	Raw     interface{}
+}
+
+// Renderer is implemented by each output format. Header is called once
+// with the column titles, Row once per data row, and Close after the
+// last row to flush the result to w.
+type Renderer interface {
+	Header(cols []string)
+	Row(cells []Cell)
+	Close(w io.Writer) error
+}
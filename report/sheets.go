@@ -0,0 +1,79 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AddClusterSheet writes the 按标记汇总 sheet: one row per cluster label
+// with summed credits/speed, share-of-network percentages and summed
+// GPU counts for each catalog model.
+func (x *XLSXRenderer) AddClusterSheet(summaries []ClusterSummary, gpuNames []string) error {
+	sheet, err := x.AddSheet("按标记汇总")
+	if err != nil {
+		return err
+	}
+
+	header := []string{"标记", "累计出块奖励", "昨日奖励", "节点速率(M s/s)", "奖励占比%", "速率占比%"}
+	for _, name := range gpuNames {
+		header = append(header, "GPU数量/"+name)
+	}
+	writeHeaderRow(sheet, header)
+
+	for _, s := range summaries {
+		row := sheet.AddRow()
+		row.AddCell().Value = s.Label
+		row.AddCell().SetFloatWithFormat(s.TotalCredits, "0.00")
+		row.AddCell().SetFloatWithFormat(s.DailyCredits, "0.00")
+		row.AddCell().SetFloatWithFormat(s.NetworkSpeedM, "0.00")
+		row.AddCell().SetFloatWithFormat(s.CreditShare, "0.00")
+		row.AddCell().SetFloatWithFormat(s.SpeedShare, "0.00")
+		for _, name := range gpuNames {
+			row.AddCell().SetInt(s.GPUCounts[name])
+		}
+	}
+	return nil
+}
+
+// AddOverviewSheet writes the 全网概览 sheet: a handful of network-wide
+// totals and the Gini coefficient, one metric per row.
+func (x *XLSXRenderer) AddOverviewSheet(o NetworkOverview) error {
+	sheet, err := x.AddSheet("全网概览")
+	if err != nil {
+		return err
+	}
+
+	writeHeaderRow(sheet, []string{"指标", "数值"})
+	metric := func(name, value string) {
+		row := sheet.AddRow()
+		row.AddCell().Value = name
+		row.AddCell().Value = value
+	}
+
+	metric("累计出块奖励总量", formatFloat(o.TotalCredits))
+	metric("全网节点速率总量(M s/s)", formatFloat(o.TotalSpeedM))
+	metric("活跃地址数", strconv.Itoa(o.ActiveAddresses))
+	metric("Top10地址奖励占比%", formatFloat(o.Top10Share))
+	metric("Gini系数(去中心化指标)", fmt.Sprintf("%.4f", o.Gini))
+	return nil
+}
+
+// AddAlertSheet writes the 告警 sheet: clusters whose daily-reward
+// share dropped by more than --alert-threshold versus a stored
+// previous snapshot.
+func (x *XLSXRenderer) AddAlertSheet(alerts []Alert) error {
+	sheet, err := x.AddSheet("告警")
+	if err != nil {
+		return err
+	}
+
+	writeHeaderRow(sheet, []string{"标记", "此前占比%", "当前占比%", "降幅(百分点)"})
+	for _, a := range alerts {
+		row := sheet.AddRow()
+		row.AddCell().Value = a.Label
+		row.AddCell().SetFloatWithFormat(a.PrevShare, "0.00")
+		row.AddCell().SetFloatWithFormat(a.CurrentShare, "0.00")
+		row.AddCell().SetFloatWithFormat(a.DropPct, "0.00")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package report
+
+import (
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// TableRenderer prints a pretty, left-aligned terminal table with bold
+// headers so operators can read the ranking over SSH without pulling
+// down a spreadsheet.
+type TableRenderer struct {
+	headers []string
+	rows    [][]string
+}
+
+func NewTableRenderer() *TableRenderer {
+	return &TableRenderer{}
+}
+
+func (t *TableRenderer) Header(cols []string) {
+	t.headers = cols
+}
+
+func (t *TableRenderer) Row(cells []Cell) {
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		row[i] = cell.Display
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (t *TableRenderer) Close(w io.Writer) error {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(t.headers)
+	table.SetAutoWrapText(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+
+	headerColors := make([]tablewriter.Colors, len(t.headers))
+	for i := range headerColors {
+		headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiCyanColor}
+	}
+	table.SetHeaderColor(headerColors...)
+
+	table.AppendBulk(t.rows)
+	table.Render()
+	return nil
+}
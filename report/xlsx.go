@@ -0,0 +1,78 @@
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/tealeg/xlsx/v3"
+)
+
+// XLSXRenderer writes rows into the detail sheet of an xlsx workbook,
+// matching the header styling the tool has always used (bold 12pt
+// Calibri); AddSheet appends further sheets to the same workbook.
+type XLSXRenderer struct {
+	file  *xlsx.File
+	sheet *xlsx.Sheet
+}
+
+// NewXLSXRenderer creates the workbook and its detail sheet up front so
+// Header/Row can append to it directly.
+func NewXLSXRenderer(sheetName string) (*XLSXRenderer, error) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("error creating sheet: %v", err)
+	}
+	return &XLSXRenderer{file: file, sheet: sheet}, nil
+}
+
+func (x *XLSXRenderer) Header(cols []string) {
+	writeHeaderRow(x.sheet, cols)
+}
+
+// writeHeaderRow appends a bold-header row to sheet, shared by the
+// detail sheet and every summary sheet added via AddSheet.
+func writeHeaderRow(sheet *xlsx.Sheet, cols []string) {
+	headerStyle := xlsx.NewStyle()
+	headerFont := xlsx.NewFont(12, "Calibri")
+	headerFont.Bold = true
+	headerStyle.Font = *headerFont
+
+	row := sheet.AddRow()
+	for _, h := range cols {
+		cell := row.AddCell()
+		cell.Value = h
+		cell.SetStyle(headerStyle)
+	}
+}
+
+// Row appends a data row, writing each cell's Raw value with the
+// matching numeric setter (so Excel sort/sum/formulas work on it) and
+// falling back to the display string for anything that isn't a float64
+// or int, same as the summary sheets in sheets.go.
+func (x *XLSXRenderer) Row(cells []Cell) {
+	row := x.sheet.AddRow()
+	for _, c := range cells {
+		cell := row.AddCell()
+		switch v := c.Raw.(type) {
+		case float64:
+			cell.SetFloatWithFormat(v, "0.00")
+		case int:
+			cell.SetInt(v)
+		default:
+			cell.Value = c.Display
+		}
+	}
+}
+
+// Close writes the whole workbook, every sheet included, to w.
+func (x *XLSXRenderer) Close(w io.Writer) error {
+	return x.file.Write(w)
+}
+
+// AddSheet appends a new, empty sheet to the workbook for callers that
+// want to add summary sheets (see AddClusterSheet, AddOverviewSheet,
+// AddAlertSheet) alongside the per-address detail sheet.
+func (x *XLSXRenderer) AddSheet(name string) (*xlsx.Sheet, error) {
+	return x.file.AddSheet(name)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Kunkka1103/prover-rank-fetcher/catalog"
+	"github.com/Kunkka1103/prover-rank-fetcher/cluster"
+	"github.com/Kunkka1103/prover-rank-fetcher/config"
+	"github.com/Kunkka1103/prover-rank-fetcher/mailer"
+	"github.com/Kunkka1103/prover-rank-fetcher/report"
+	"github.com/robfig/cron/v3"
+	"github.com/urfave/cli/v2"
+)
+
+// serveCommand implements `prover-rank-fetcher serve`: a long-running
+// process that fetches and emails yesterday's report on a cron
+// schedule, instead of requiring a wrapper cron job around a one-shot
+// run.
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Run as a daemon that fetches, renders and emails the report on a cron schedule",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "schedule", Usage: "Cron expression for when to run, e.g. \"0 9 * * *\"", Required: true},
+			&cli.StringFlag{Name: "config", Usage: "Path to the YAML/JSON mail config", Required: true},
+			&cli.StringFlag{Name: "cluster_file", Usage: "Path to the cluster-name file", Required: true},
+			&cli.StringFlag{
+				Name:  "api_url",
+				Usage: "API URL for fetching prover rank list",
+				Value: "http://localhost:8088/api/v1/provers/prover_rank_list",
+			},
+			&cli.StringFlag{Name: "catalog", Usage: "Path to a YAML GPU hashrate catalog. Unset falls back to the built-in 3080/4090 table."},
+			&cli.StringFlag{Name: "cluster-encoding", Usage: "Charset of --cluster_file: utf-8, gbk, gb18030 or big5. Unset auto-detects it."},
+			&cli.StringFlag{Name: "output-dir", Usage: "Directory to write the xlsx report into", Value: "."},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Log the recipients and attachment path instead of sending email"},
+			&cli.IntFlag{Name: "health-port", Usage: "Port to serve /healthz on", Value: 8080},
+		},
+		Action: func(c *cli.Context) error {
+			cfg, err := config.Load(c.String("config"))
+			if err != nil {
+				return fmt.Errorf("error loading --config: %v", err)
+			}
+
+			healthy := startHealthzServer(c.Int("health-port"))
+
+			sched := c.String("schedule")
+			clusterFilePath := c.String("cluster_file")
+			clusterEncoding := c.String("cluster-encoding")
+			apiURL := c.String("api_url")
+			catalogPath := c.String("catalog")
+			outputDir := c.String("output-dir")
+			dryRun := c.Bool("dry-run")
+
+			scheduler := cron.New()
+			_, err = scheduler.AddFunc(sched, func() {
+				if err := runDailyReport(apiURL, clusterFilePath, clusterEncoding, catalogPath, outputDir, cfg, dryRun); err != nil {
+					log.Printf("scheduled report run failed: %v", err)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("error parsing --schedule: %v", err)
+			}
+
+			scheduler.Start()
+			log.Printf("serve started, schedule=%q dry-run=%v", sched, dryRun)
+
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+			<-sig
+
+			log.Println("shutting down")
+			<-scheduler.Stop().Done()
+			return healthy.Shutdown(context.Background())
+		},
+	}
+}
+
+// startHealthzServer starts a minimal HTTP server with a /healthz
+// endpoint so the daemon can run under a Kubernetes liveness probe.
+func startHealthzServer(port int) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	})
+
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("healthz server stopped: %v", err)
+		}
+	}()
+	return srv
+}
+
+// previousDayWindow returns the [start, end] unix timestamps for the
+// calendar day before now, in the local timezone: start is that day's
+// midnight and end its last second (23:59:59).
+func previousDayWindow(now time.Time) (start, end int64) {
+	local := now.Local()
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	yesterday := today.AddDate(0, 0, -1)
+	return yesterday.Unix(), today.Unix() - 1
+}
+
+// runDailyReport fetches yesterday's window, renders it to xlsx, and
+// emails it (or logs it, under --dry-run). API failures are retried
+// with exponential backoff since this runs unattended.
+func runDailyReport(apiURL, clusterFilePath, clusterEncoding, catalogPath, outputDir string, cfg *config.Config, dryRun bool) error {
+	startTime, endTime := previousDayWindow(time.Now())
+
+	var provers []Prover
+	err := retryWithBackoff(3, time.Second, func() error {
+		var fetchErr error
+		provers, fetchErr = fetchProverRankList(apiURL, startTime, endTime)
+		return fetchErr
+	})
+	if err != nil {
+		return fmt.Errorf("error fetching prover rank list: %v", err)
+	}
+
+	clusters, err := cluster.Load(clusterFilePath, clusterEncoding)
+	if err != nil {
+		return fmt.Errorf("error reading cluster file: %v", err)
+	}
+
+	cat := catalog.Default()
+	if catalogPath != "" {
+		cat, err = catalog.Load(catalogPath)
+		if err != nil {
+			return fmt.Errorf("error loading --catalog: %v", err)
+		}
+	}
+
+	rows := buildRows(provers, clusters, cat)
+	cols := append(append([]report.Column{}, report.Columns...), report.GPUColumns(gpuNames(cat), cat.HasPricing())...)
+
+	baseName := fmt.Sprintf("aleo大矿工统计-%s", time.Unix(startTime, 0).Format("2006-01-02"))
+	renderer, err := report.NewXLSXRenderer("Sheet1")
+	if err != nil {
+		return err
+	}
+	if err := renderReport(renderer, cols, rows, "xlsx", outputDir, baseName, true, nil); err != nil {
+		return err
+	}
+	attachmentPath := fmt.Sprintf("%s/%s.xlsx", outputDir, baseName)
+
+	data := mailer.TemplateData{Date: time.Unix(startTime, 0).Format("2006-01-02")}
+	if len(rows) > 0 {
+		data.TopAddress = rows[0].Address
+		data.TopReward = fmt.Sprintf("%.2f", rows[0].TotalCredits)
+	}
+
+	if dryRun {
+		log.Printf("[dry-run] would email %v (cc %v) with attachment %s", cfg.Mail.To, cfg.Mail.Cc, attachmentPath)
+		return nil
+	}
+
+	return retryWithBackoff(3, time.Second, func() error {
+		return mailer.Send(cfg, data, attachmentPath)
+	})
+}
+
+// retryWithBackoff calls fn up to attempts times, doubling the delay
+// after each failure, and returns the last error if none succeed.
+func retryWithBackoff(attempts int, base time.Duration, fn func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if i == attempts-1 {
+			break
+		}
+		delay := base * time.Duration(1<<i)
+		log.Printf("attempt %d/%d failed: %v, retrying in %s", i+1, attempts, err, delay)
+		time.Sleep(delay)
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+// Package store persists fetched prover snapshots so the tool can
+// compare two windows or chart a trend over time, instead of only ever
+// reporting on the window it just fetched.
+package store
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Snapshot is one prover's data for one fetch window. The combination
+// of address, start time and end time identifies a fetch uniquely, so
+// re-running the tool for the same window upserts instead of
+// duplicating rows.
+type Snapshot struct {
+	ID                           uint   `gorm:"primaryKey"`
+	Address                      string `gorm:"uniqueIndex:idx_prover_snapshot_window;size:128"`
+	StartTime                    int64  `gorm:"uniqueIndex:idx_prover_snapshot_window"`
+	EndTime                      int64  `gorm:"uniqueIndex:idx_prover_snapshot_window"`
+	Label                        string
+	TotalPuzzleCredits           float64
+	TotalPuzzleCreditsPercentage string
+	DailyPuzzleCredits           float64
+	DailyPuzzleCreditsPercentage string
+	NetworkSpeed                 float64
+	NetworkSpeedPercentage       string
+	FetchedAt                    time.Time
+}
+
+func (Snapshot) TableName() string { return "prover_snapshots" }
+
+// Store wraps the GORM handle the rest of the package methods operate
+// on.
+type Store struct {
+	db *gorm.DB
+}
+
+// Open connects to dsn and migrates the prover_snapshots table. The
+// dialect is chosen from the DSN prefix: postgres://... and mysql://...
+// select those drivers, anything else (including a bare file path) is
+// treated as a SQLite file.
+func Open(dsn string) (*Store, error) {
+	dialector, err := dialectorFor(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.AutoMigrate(&Snapshot{}); err != nil {
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func dialectorFor(dsn string) (gorm.Dialector, error) {
+	switch {
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		return postgres.Open(dsn), nil
+	case strings.HasPrefix(dsn, "mysql://"):
+		return mysql.Open(strings.TrimPrefix(dsn, "mysql://")), nil
+	default:
+		return sqlite.Open(dsn), nil
+	}
+}
+
+// Upsert writes snapshots, updating the data columns in place when a
+// row for the same (address, start_time, end_time) already exists.
+func (s *Store) Upsert(snapshots []Snapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	return s.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "address"}, {Name: "start_time"}, {Name: "end_time"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"label",
+			"total_puzzle_credits", "total_puzzle_credits_percentage",
+			"daily_puzzle_credits", "daily_puzzle_credits_percentage",
+			"network_speed", "network_speed_percentage",
+			"fetched_at",
+		}),
+	}).Create(&snapshots).Error
+}
+
+// Window returns every snapshot fetched for the exact (startTime,
+// endTime) window, keyed by address for easy lookup during comparison.
+func (s *Store) Window(startTime, endTime int64) (map[string]Snapshot, error) {
+	var rows []Snapshot
+	if err := s.db.Where("start_time = ? AND end_time = ?", startTime, endTime).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	byAddress := make(map[string]Snapshot, len(rows))
+	for _, row := range rows {
+		byAddress[row.Address] = row
+	}
+	return byAddress, nil
+}
+
+// Trend returns every snapshot between startTime and endTime (inclusive)
+// for the given address and/or cluster label, ordered oldest first.
+// Either filter may be empty to match all addresses/labels.
+func (s *Store) Trend(address, label string, startTime, endTime int64) ([]Snapshot, error) {
+	q := s.db.Where("start_time >= ? AND end_time <= ?", startTime, endTime)
+	if address != "" {
+		q = q.Where("address = ?", address)
+	}
+	if label != "" {
+		q = q.Where("label = ?", label)
+	}
+
+	var rows []Snapshot
+	if err := q.Order("start_time asc").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}